@@ -0,0 +1,45 @@
+package downloader
+
+import "io"
+
+// bufferedReader owns a fixed-size buffer for a single chunk's HTTP body.
+//
+// Read blocks until the body has been fully fetched into the buffer, signaled
+// by the done channel, then serves bytes from the buffer like a bytes.Reader.
+// This lets a chanMultiReader hand the reader to a caller before the chunk's
+// download has actually finished.
+type bufferedReader struct {
+	buf  []byte
+	pos  int
+	done chan struct{}
+	err  error
+}
+
+func newBufferedReader() *bufferedReader {
+	return &bufferedReader{done: make(chan struct{})}
+}
+
+// fill reads r fully into the buffer and signals done.
+func (br *bufferedReader) fill(r io.Reader) {
+	br.buf, br.err = io.ReadAll(r)
+	close(br.done)
+}
+
+// fail signals done without ever filling the buffer, so Read reports err.
+func (br *bufferedReader) fail(err error) {
+	br.err = err
+	close(br.done)
+}
+
+func (br *bufferedReader) Read(p []byte) (n int, err error) {
+	<-br.done
+	if br.err != nil {
+		return 0, br.err
+	}
+	if br.pos >= len(br.buf) {
+		return 0, io.EOF
+	}
+	n = copy(p, br.buf[br.pos:])
+	br.pos += n
+	return n, nil
+}
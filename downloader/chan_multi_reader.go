@@ -0,0 +1,46 @@
+package downloader
+
+import "io"
+
+// chanMultiReader stitches together readers delivered on a channel, in the
+// order they arrive, presenting them as a single io.Reader.
+//
+// Chunk readers are sent to the channel in start-offset order, so as soon as
+// chunk N is exhausted the consumer moves on to chunk N+1's bufferedReader
+// without waiting for chunks beyond that to finish downloading.
+type chanMultiReader struct {
+	readers <-chan io.Reader
+	current io.Reader
+}
+
+func newChanMultiReader(readers <-chan io.Reader) *chanMultiReader {
+	return &chanMultiReader{readers: readers}
+}
+
+func (cr *chanMultiReader) Read(p []byte) (n int, err error) {
+	for {
+		if cr.current == nil {
+			reader, ok := <-cr.readers
+			if !ok {
+				return 0, io.EOF
+			}
+			cr.current = reader
+		}
+
+		n, err = cr.current.Read(p)
+		if err == io.EOF {
+			cr.current = nil
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+// Close implements io.Closer. There is nothing to release: the underlying
+// bufferedReaders are plain in-memory buffers.
+func (cr *chanMultiReader) Close() error {
+	return nil
+}
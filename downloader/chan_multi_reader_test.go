@@ -0,0 +1,36 @@
+package downloader
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestChanMultiReaderStitchesInOrder(t *testing.T) {
+	readers := make(chan io.Reader, 3)
+	readers <- strings.NewReader("foo")
+	readers <- strings.NewReader("bar")
+	readers <- strings.NewReader("baz")
+	close(readers)
+
+	got, err := io.ReadAll(newChanMultiReader(readers))
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if string(got) != "foobarbaz" {
+		t.Fatalf("ReadAll() = %q, want %q", got, "foobarbaz")
+	}
+}
+
+func TestChanMultiReaderEmpty(t *testing.T) {
+	readers := make(chan io.Reader)
+	close(readers)
+
+	got, err := io.ReadAll(newChanMultiReader(readers))
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("ReadAll() = %q, want empty", got)
+	}
+}
@@ -0,0 +1,49 @@
+package downloader
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/url"
+	"sort"
+)
+
+// rendezvousOrder ranks hosts for the given (path, sliceIndex) pair by rendezvous (highest random
+// weight) hashing, highest score first. The top host is the one normally used; the rest are the
+// failover order if it returns a non-206 response or a network error.
+func rendezvousOrder(hosts []string, path string, sliceIndex int64) []string {
+	ordered := make([]string, len(hosts))
+	copy(ordered, hosts)
+
+	sort.Slice(ordered, func(i, j int) bool {
+		return rendezvousScore(ordered[i], path, sliceIndex) > rendezvousScore(ordered[j], path, sliceIndex)
+	})
+	return ordered
+}
+
+func rendezvousScore(host, path string, sliceIndex int64) uint64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s|%s|%d", host, path, sliceIndex)
+	return h.Sum64()
+}
+
+// urlPath extracts rawURL's path, so hashing ignores query strings (e.g. the signed-URL timestamps and
+// tokens a CDN commonly appends), which would otherwise make "the same" resource hash differently on
+// every request and defeat mirror affinity entirely. If rawURL fails to parse, it is used as-is.
+func urlPath(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return u.Path
+}
+
+// rewriteHost returns rawURL with its host replaced by host, so a chunk sub-request can be routed to a
+// mirror while keeping the original path, query, and scheme.
+func rewriteHost(rawURL string, host string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	u.Host = host
+	return u.String(), nil
+}
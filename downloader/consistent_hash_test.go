@@ -0,0 +1,46 @@
+package downloader
+
+import "testing"
+
+func TestRendezvousOrderIsDeterministic(t *testing.T) {
+	hosts := []string{"a.example.com", "b.example.com", "c.example.com"}
+
+	first := rendezvousOrder(hosts, "/videos/1.mp4", 3)
+	second := rendezvousOrder(hosts, "/videos/1.mp4", 3)
+
+	if len(first) != len(hosts) {
+		t.Fatalf("got %d hosts, want %d", len(first), len(hosts))
+	}
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("rendezvousOrder is not deterministic: %v vs %v", first, second)
+		}
+	}
+}
+
+func TestRendezvousOrderVariesByKey(t *testing.T) {
+	hosts := []string{"a.example.com", "b.example.com", "c.example.com", "d.example.com"}
+
+	top := rendezvousOrder(hosts, "/videos/1.mp4", 0)[0]
+	differentSlice := rendezvousOrder(hosts, "/videos/1.mp4", 1)[0]
+	differentPath := rendezvousOrder(hosts, "/videos/2.mp4", 0)[0]
+
+	if top == differentSlice && top == differentPath {
+		t.Fatalf("expected the top host to vary across at least one of slice index or path, always got %q", top)
+	}
+}
+
+func TestUrlPathStripsQuery(t *testing.T) {
+	got := urlPath("https://cdn.example.com/videos/1.mp4?token=abc&expires=123")
+	want := "/videos/1.mp4"
+	if got != want {
+		t.Fatalf("urlPath() = %q, want %q", got, want)
+	}
+}
+
+func TestUrlPathFallsBackOnParseError(t *testing.T) {
+	invalid := "://not-a-url"
+	if got := urlPath(invalid); got != invalid {
+		t.Fatalf("urlPath() = %q, want %q (the input unchanged)", got, invalid)
+	}
+}
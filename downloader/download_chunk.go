@@ -9,6 +9,10 @@ type DownloadChunk struct {
 	size    int64
 	current int64
 
+	// index is this chunk's position among the file's chunks, used to address its bit in a
+	// resumeState's Completed bitmap.
+	index int64
+
 	writer WriteAtWriter
 }
 
@@ -16,15 +20,25 @@ func (dc *DownloadChunk) String() string {
 	return fmt.Sprintf("DownloadChunk{start=%d, size=%d, current=%d}", dc.start, dc.size, dc.current)
 }
 
+// GetBytesRange returns the Range header value for this chunk's remaining bytes, i.e. starting at
+// dc.current rather than always at dc.start, so a retry resumes instead of re-requesting bytes that
+// were already written by a previous attempt.
 func (dc *DownloadChunk) GetBytesRange() string {
-	return fmt.Sprintf("bytes=%d-%d", dc.start, dc.start+dc.size-1)
+	return fmt.Sprintf("bytes=%d-%d", dc.start+dc.current, dc.start+dc.size-1)
 }
 
+// Write writes p at dc.start+dc.current, capping at dc.size-dc.current so a response that (incorrectly)
+// delivers more bytes than remain in this chunk can never overwrite the next chunk's region of the file.
 func (dc *DownloadChunk) Write(p []byte) (n int, err error) {
-	if dc.current >= dc.size {
+	remaining := dc.size - dc.current
+	if remaining <= 0 {
 		return 0, nil
 	}
 
+	if int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+
 	n, err = dc.writer.WriteAt(p, dc.start+dc.current)
 	dc.current += int64(n)
 	return n, err
@@ -0,0 +1,58 @@
+package downloader
+
+import "testing"
+
+func TestGetBytesRangeResumesFromCurrent(t *testing.T) {
+	dc := &DownloadChunk{start: 100, size: 50, current: 0}
+	if got, want := dc.GetBytesRange(), "bytes=100-149"; got != want {
+		t.Fatalf("GetBytesRange() = %q, want %q", got, want)
+	}
+
+	dc.current = 20
+	if got, want := dc.GetBytesRange(), "bytes=120-149"; got != want {
+		t.Fatalf("after a partial write, GetBytesRange() = %q, want %q", got, want)
+	}
+}
+
+func TestWriteCapsAtChunkSize(t *testing.T) {
+	buf := &memoryWriter{buf: make([]byte, 10)}
+	dc := &DownloadChunk{start: 0, size: 10, writer: buf}
+
+	n, err := dc.Write([]byte("0123456789ABCD"))
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if n != 10 {
+		t.Fatalf("Write() = %d bytes, want 10 (capped at chunk size)", n)
+	}
+	if dc.current != 10 {
+		t.Fatalf("dc.current = %d, want 10", dc.current)
+	}
+	if string(buf.buf) != "0123456789" {
+		t.Fatalf("buf = %q, want %q", buf.buf, "0123456789")
+	}
+
+	n, err = dc.Write([]byte("overflow"))
+	if err != nil {
+		t.Fatalf("Write() on a full chunk error = %v", err)
+	}
+	if n != 0 {
+		t.Fatalf("Write() on a full chunk = %d bytes, want 0", n)
+	}
+}
+
+func TestWriteResumesAtCurrentOffset(t *testing.T) {
+	buf := &memoryWriter{buf: make([]byte, 10)}
+	dc := &DownloadChunk{start: 0, size: 10, writer: buf}
+
+	if _, err := dc.Write([]byte("01234")); err != nil {
+		t.Fatalf("first Write() error = %v", err)
+	}
+	if _, err := dc.Write([]byte("56789")); err != nil {
+		t.Fatalf("second Write() error = %v", err)
+	}
+
+	if string(buf.buf) != "0123456789" {
+		t.Fatalf("buf = %q, want %q", buf.buf, "0123456789")
+	}
+}
@@ -1,11 +1,14 @@
 package downloader
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"io"
 	"net/http"
 	"strconv"
 	"sync"
+	"time"
 )
 
 // Internally use in Downloader
@@ -25,6 +28,67 @@ type downloadManager struct {
 	totalBytes int64
 	written    int64
 	err        error
+
+	// chunkSize is the size determineNumParts computed for this download. It is kept here rather than
+	// written back into cfg.ChunkSize, since cfg is shared (the same *DownloaderConfig is handed to
+	// every downloadManager DownloadManifest spawns), and writing to it from concurrently running
+	// downloads is a data race.
+	chunkSize int64
+
+	// requestSem, if set, bounds the number of in-flight HTTP requests across this downloadManager and
+	// any siblings it shares the semaphore with, e.g. other files in the same DownloadManifest call.
+	requestSem *semaphore
+
+	// resumeState is non-nil when `DownloaderConfig.Resume` is set, and tracks which chunks have
+	// already been downloaded so a restarted download can skip them.
+	resumeState *resumeState
+}
+
+func (dm *downloadManager) acquireRequest() {
+	if dm.requestSem != nil {
+		dm.requestSem.acquire()
+	}
+}
+
+func (dm *downloadManager) releaseRequest() {
+	if dm.requestSem != nil {
+		dm.requestSem.release()
+	}
+}
+
+// httpClient returns the configured HTTPClient, falling back to http.DefaultClient.
+func (dm *downloadManager) httpClient() HTTPClient {
+	if dm.cfg.HTTPClient != nil {
+		return dm.cfg.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// retryPolicy returns the configured RetryPolicy, falling back to defaultRetryPolicy.
+func (dm *downloadManager) retryPolicy() *RetryPolicy {
+	if dm.cfg.RetryPolicy != nil {
+		return dm.cfg.RetryPolicy
+	}
+	return defaultRetryPolicy()
+}
+
+// parseRetryAfter parses a Retry-After header, given either as a number of seconds or an HTTP-date.
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if when, err := http.ParseTime(header); err == nil {
+		if d := time.Until(when); d > 0 {
+			return d
+		}
+	}
+
+	return 0
 }
 
 // ---------------------------- Getter & Setter ----------------------------
@@ -73,16 +137,22 @@ func (dm *downloadManager) AddWritten(written int64) {
 // ---------------------------- Calculate Before Download ----------------------------
 
 func (dm *downloadManager) doHeadRequest(url string) (int64, error) {
-	resp, err := http.Head(url)
-	// Is Response != 200 return error
-	if resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusForbidden {
-		return 0, &DownloadError{Message: "Response status code is not 200", Err: http.ErrNotSupported}
+	req, err := http.NewRequestWithContext(dm.ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return 0, err
 	}
 
+	resp, err := dm.httpClient().Do(req)
 	if err != nil {
 		return 0, err
 	}
 	defer resp.Body.Close()
+
+	// Is Response != 200 return error
+	if resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusForbidden {
+		return 0, &DownloadError{Message: "Response status code is not 200", Err: http.ErrNotSupported}
+	}
+
 	return resp.ContentLength, nil
 }
 
@@ -92,21 +162,22 @@ func (dm *downloadManager) doHeadRequest(url string) (int64, error) {
 //
 // And return the size of the file in the Content-Range header
 func (dm *downloadManager) doGetZero(url string) (int64, error) {
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	req, err := http.NewRequestWithContext(dm.ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return 0, &DownloadError{Message: "Failed to create request", Err: err}
 	}
 
 	req.Header.Set("Range", "bytes=0-0")
-	resp, err := http.DefaultClient.Do(req)
-	// Is Response != 206 return error
-	if resp.StatusCode != http.StatusPartialContent {
-		return 0, &DownloadError{Message: "Response status code is not 206", Err: err}
-	}
-
+	resp, err := dm.httpClient().Do(req)
 	if err != nil {
 		return 0, &DownloadError{Message: "Failed to do request", Err: err}
 	}
+	defer resp.Body.Close()
+
+	// Is Response != 206 return error
+	if resp.StatusCode != http.StatusPartialContent {
+		return 0, &DownloadError{Message: "Response status code is not 206", Err: nil}
+	}
 
 	contentRange := resp.Header.Get("Content-Range")
 	fileSize := contentRange[len("bytes 0-0/"):]
@@ -147,6 +218,24 @@ func (dm *downloadManager) getFileSize(url string) (int64, error) {
 	return size, nil
 }
 
+// getValidators fetches the ETag and Last-Modified headers for url, used by resume to detect whether
+// the remote file changed since a previous attempt. Empty strings are returned if the request fails or
+// the server doesn't send them, which `resumeState.matches` treats as "unknown" rather than "matches".
+func (dm *downloadManager) getValidators(url string) (etag string, lastModified string) {
+	req, err := http.NewRequestWithContext(dm.ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return "", ""
+	}
+
+	resp, err := dm.httpClient().Do(req)
+	if err != nil {
+		return "", ""
+	}
+	defer resp.Body.Close()
+
+	return resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+}
+
 // Calculate the number of chunks will be split.
 //
 // The parameter is the total size of the file.
@@ -181,30 +270,30 @@ type Batch []DownloadChunk
 // If `MaxConcurrentDownloads` greater than 0, the chunks will be downloaded concurrently by batch.
 // The number of batch is the number of chunks divided by `MaxConcurrentDownloads`.
 // Each batch contains (number of chunks / number of batch) chunks.
-func (dm *downloadManager) batchChunks(numChunks int64, writer WriteAtWriter) []Batch {
+func (dm *downloadManager) batchChunks(numChunks int64, chunkSize int64, writer WriteAtWriter) []Batch {
 	if dm.cfg.MaxConcurrentDownloads == -1 {
-		return dm.batchChunksByOne(numChunks, writer)
+		return dm.batchChunksByOne(numChunks, chunkSize, writer)
 	}
-	return dm.batchChunksByMaxConcurrentParts(numChunks, writer)
+	return dm.batchChunksByMaxConcurrentParts(numChunks, chunkSize, writer)
 }
 
-func (dm *downloadManager) batchChunksByOne(numChunks int64, writer WriteAtWriter) []Batch {
+func (dm *downloadManager) batchChunksByOne(numChunks int64, chunkSize int64, writer WriteAtWriter) []Batch {
 	batches := make([]Batch, numChunks)
 	for i := int64(0); i < numChunks; i++ {
-		start := i * dm.cfg.ChunkSize
-		size := dm.cfg.ChunkSize
+		start := i * chunkSize
+		size := chunkSize
 		if start+size > dm.totalBytes {
 			size = dm.totalBytes - start
 		}
 		batches[i] = make([]DownloadChunk, 1)
-		batches[i][0] = DownloadChunk{start: start, size: size, writer: writer}
+		batches[i][0] = DownloadChunk{start: start, size: size, index: i, writer: writer}
 	}
 	return batches
 }
 
-func (dm *downloadManager) batchChunksByMaxConcurrentParts(numChunks int64, writer WriteAtWriter) []Batch {
+func (dm *downloadManager) batchChunksByMaxConcurrentParts(numChunks int64, chunkSize int64, writer WriteAtWriter) []Batch {
 	if numChunks <= int64(dm.cfg.MaxConcurrentDownloads) {
-		return dm.batchChunksByOne(numChunks, writer)
+		return dm.batchChunksByOne(numChunks, chunkSize, writer)
 	}
 
 	batchSize := numChunks / int64(dm.cfg.MaxConcurrentDownloads)
@@ -215,12 +304,12 @@ func (dm *downloadManager) batchChunksByMaxConcurrentParts(numChunks int64, writ
 
 	for i := int64(0); i < numChunks; i++ {
 		batchIndex := i / batchSize
-		start := i * dm.cfg.ChunkSize
-		size := dm.cfg.ChunkSize
+		start := i * chunkSize
+		size := chunkSize
 		if start+size > dm.totalBytes {
 			size = dm.totalBytes - start
 		}
-		batches[batchIndex] = append(batches[batchIndex], DownloadChunk{start: start, size: size, writer: writer})
+		batches[batchIndex] = append(batches[batchIndex], DownloadChunk{start: start, size: size, index: i, writer: writer})
 	}
 	return batches
 }
@@ -231,13 +320,23 @@ func (dm *downloadManager) batchChunksByMaxConcurrentParts(numChunks int64, writ
 func (dm *downloadManager) download() (int64, error) {
 	fileSize, err := dm.getFileSize(dm.url)
 	if err != nil {
+		dm.onFinish(0, err)
 		return 0, err
 	}
 	dm.SetTotalBytes(fileSize)
+	dm.onStart(fileSize)
 
 	numChunks, chunkSize := dm.determineNumParts(fileSize)
-	dm.cfg.ChunkSize = chunkSize
-	batches := dm.batchChunks(numChunks, dm.writer)
+	dm.chunkSize = chunkSize
+
+	if dm.cfg.Resume {
+		if err := dm.loadOrInitResumeState(numChunks, fileSize, chunkSize); err != nil {
+			dm.onFinish(0, err)
+			return 0, err
+		}
+	}
+
+	batches := dm.batchChunks(numChunks, chunkSize, dm.writer)
 
 	for i := 0; i < len(batches); i++ {
 		dm.wg.Add(1)
@@ -246,10 +345,127 @@ func (dm *downloadManager) download() (int64, error) {
 	dm.wg.Wait()
 
 	if dm.GetError() != nil {
+		dm.onFinish(dm.GetWritten(), dm.GetError())
 		return 0, dm.GetError()
 	}
 
-	return dm.GetWritten(), nil
+	if dm.cfg.Resume {
+		removeSidecar(dm.filename)
+	}
+
+	written := dm.GetWritten()
+	dm.onFinish(written, nil)
+	return written, nil
+}
+
+// loadOrInitResumeState reuses dm.filename's sidecar if it still describes the same remote file and
+// chunk layout, discarding it otherwise, then sets dm.resumeState.
+func (dm *downloadManager) loadOrInitResumeState(numChunks int64, fileSize int64, chunkSize int64) error {
+	etag, lastModified := dm.getValidators(dm.url)
+
+	if existing, err := loadResumeState(dm.filename); err == nil {
+		if existing.matches(dm.url, fileSize, etag, lastModified) &&
+			existing.ChunkSize == chunkSize && int64(len(existing.Completed)) == numChunks {
+			dm.resumeState = existing
+			return nil
+		}
+		removeSidecar(dm.filename)
+	}
+
+	state := &resumeState{
+		URL:          dm.url,
+		TotalSize:    fileSize,
+		ETag:         etag,
+		LastModified: lastModified,
+		ChunkSize:    chunkSize,
+		Completed:    make([]bool, numChunks),
+	}
+	if err := state.save(dm.filename); err != nil {
+		return &DownloadError{Message: "Failed to create resume sidecar", Err: err}
+	}
+
+	dm.resumeState = state
+	return nil
+}
+
+// ---------------------------- Fetch (streaming) ----------------------------
+
+// Fetch streams the file from the given url, without writing it to disk.
+//
+// Chunks are fetched concurrently in the background by a workQueue bounded by
+// `MaxConcurrentDownloads`, each into its own bufferedReader. The returned
+// io.ReadCloser stitches those bufferedReaders together in start-offset order
+// via a chanMultiReader, so the caller can start consuming bytes as soon as
+// the first chunk is ready instead of waiting for the whole file.
+func (dm *downloadManager) fetch() (io.ReadCloser, int64, error) {
+	fileSize, err := dm.getFileSize(dm.url)
+	if err != nil {
+		return nil, 0, err
+	}
+	dm.SetTotalBytes(fileSize)
+	dm.onStart(fileSize)
+
+	numChunks, chunkSize := dm.determineNumParts(fileSize)
+	dm.chunkSize = chunkSize
+
+	workers := dm.cfg.MaxConcurrentDownloads
+	if workers <= 0 {
+		workers = int(numChunks)
+	}
+
+	readers := make(chan io.Reader, numChunks)
+	wq := newWorkQueue(workers, func(job chunkJob) {
+		dm.fetchChunk(job)
+	})
+
+	go func() {
+		for i := int64(0); i < numChunks; i++ {
+			start := i * chunkSize
+			size := chunkSize
+			if start+size > fileSize {
+				size = fileSize - start
+			}
+
+			reader := newBufferedReader()
+			readers <- reader
+			wq.submit(chunkJob{start: start, size: size, index: i, reader: reader})
+		}
+		close(readers)
+		wq.close()
+		dm.onFinish(dm.GetWritten(), dm.GetError())
+	}()
+
+	return io.NopCloser(newChanMultiReader(readers)), fileSize, nil
+}
+
+// fetchChunk downloads a single byte range for Fetch and fills job.reader with the result, buffering it
+// in memory since a streaming Fetch has no destination file to address with WriteAt. It reuses
+// downloadChunk, the same retrying entry point the Download() path uses, dispatching to the mirror
+// fleet when ConsistentHashing is configured or to dm.url directly otherwise, so Fetch gets the same
+// RetryPolicy backoff and chunk.current-resuming retries as Download instead of a hand-rolled duplicate:
+// a failed attempt here only ever advances chunk.current on the bytes it actually wrote, so a retry
+// resumes instead of re-buffering the whole range from scratch.
+//
+// downloadChunk already reports onChunkStart/onChunkProgress/onChunkFinish and records written/error
+// state, the same as the Download() path, so fetchChunk does not duplicate that here.
+func (dm *downloadManager) fetchChunk(job chunkJob) {
+	buf := &memoryWriter{buf: make([]byte, job.size)}
+	chunk := &DownloadChunk{
+		start:  job.start,
+		size:   job.size,
+		index:  job.index,
+		writer: &offsetWriter{base: job.start, inner: buf},
+	}
+
+	num, err := dm.downloadChunk(chunk)
+	dm.AddWritten(num)
+	if err != nil {
+		dm.SetError(err)
+		job.reader.fail(err)
+		return
+	}
+
+	job.reader.fill(bytes.NewReader(buf.buf))
 }
 
 // Download the batch of chunks
@@ -257,31 +473,83 @@ func (dm *downloadManager) downloadBatch(batch []DownloadChunk) {
 	defer dm.wg.Done()
 
 	for i := 0; i < len(batch); i++ {
-		num, err := dm.downloadChunk(&batch[i])
+		chunk := &batch[i]
+
+		if dm.resumeState != nil && dm.resumeState.isComplete(chunk.index) {
+			dm.AddWritten(chunk.size)
+			continue
+		}
+
+		num, err := dm.downloadChunk(chunk)
 		if err != nil {
 			dm.SetError(err)
 			return
 		}
 		dm.AddWritten(num)
+
+		if dm.resumeState != nil {
+			if err := dm.resumeState.markComplete(dm.filename, chunk.index); err != nil {
+				dm.SetError(&DownloadError{Message: "Failed to persist resume state", Err: err})
+				return
+			}
+		}
 	}
 }
 
-// Download the chunk
+// Download the chunk, retrying according to the configured RetryPolicy.
+//
+// Retries resume from chunk.current rather than restarting the whole chunk, since tryDownloadChunk
+// requests only the remaining bytes. The delay before each retry backs off exponentially, honors a
+// server's Retry-After header on 429/503, and is cut short if the context is canceled.
 func (dm *downloadManager) downloadChunk(chunk *DownloadChunk) (int64, error) {
-	var num int64
-	var err error
-	for i := 0; i < dm.cfg.MaxRetries; i++ {
-		num, err = dm.tryDownloadChunk(chunk)
+	policy := dm.retryPolicy()
+
+	dm.onChunkStart(int(chunk.index), chunk.size)
+	defer dm.onChunkFinish(int(chunk.index))
+
+	var total int64
+	var lastErr error
+
+	for attempt := 0; attempt < dm.cfg.MaxRetries; attempt++ {
+		if err := dm.ctx.Err(); err != nil {
+			return total, err
+		}
+
+		num, err := dm.tryDownloadChunk(chunk)
+		total += num
 		if err == nil {
-			return num, nil
+			return total, nil
+		}
+		lastErr = err
+
+		wait := policy.Delay(attempt)
+		if de, ok := err.(*DownloadError); ok {
+			if de.StatusCode != 0 && !policy.Retry(de.StatusCode) {
+				return total, err
+			}
+			if de.RetryAfter > 0 {
+				wait = de.RetryAfter
+			}
+		}
+
+		select {
+		case <-dm.ctx.Done():
+			return total, dm.ctx.Err()
+		case <-time.After(wait):
 		}
 	}
-	return num, err
+
+	return total, lastErr
 }
 
-// Try to download the chunk
+// Try to download the chunk. Only the remaining bytes, starting at chunk.current, are requested, so a
+// caller retrying a partially-failed chunk resumes instead of re-downloading it from scratch.
 func (dm *downloadManager) tryDownloadChunk(chunk *DownloadChunk) (int64, error) {
-	req, err := http.NewRequest("GET", dm.url, nil)
+	if dm.cfg.ConsistentHashing != nil {
+		return dm.tryDownloadChunkConsistentHashing(chunk)
+	}
+
+	req, err := http.NewRequestWithContext(dm.ctx, http.MethodGet, dm.url, nil)
 	if err != nil {
 		return 0, &DownloadError{Message: "Failed to create request", Err: err}
 	}
@@ -289,20 +557,123 @@ func (dm *downloadManager) tryDownloadChunk(chunk *DownloadChunk) (int64, error)
 	rangeHeader := chunk.GetBytesRange()
 	req.Header.Set("Range", rangeHeader)
 
-	resp, err := http.DefaultClient.Do(req)
+	dm.acquireRequest()
+	defer dm.releaseRequest()
+
+	resp, err := dm.httpClient().Do(req)
 	if err != nil {
 		return 0, &DownloadError{Message: "Failed to do request", Err: err}
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusPartialContent {
-		return 0, &DownloadError{Message: "Failed to download chunk", Err: err}
+		return 0, &DownloadError{
+			Message:    "Failed to download chunk",
+			Err:        err,
+			StatusCode: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
 	}
 
-	num, err := io.Copy(chunk, resp.Body)
+	counted := &countingReader{r: resp.Body, onRead: func(n int64) {
+		dm.onChunkProgress(int(chunk.index), n)
+	}}
+	num, err := io.Copy(chunk, counted)
 	if err != nil {
-		return 0, &DownloadError{Message: "Failed to write chunk", Err: err}
+		return num, &DownloadError{Message: "Failed to write chunk", Err: err}
 	}
 
 	return num, nil
 }
+
+// tryDownloadChunkConsistentHashing splits chunk's remaining bytes (starting at chunk.current, so a
+// retry resumes instead of restarting the whole chunk) into sub-ranges aligned to the configured slice
+// size, routes each sub-range to a mirror host chosen by rendezvous hashing of (path, sliceIndex), and
+// concatenates the responses in order into the chunk's WriteAt offsets.
+func (dm *downloadManager) tryDownloadChunkConsistentHashing(chunk *DownloadChunk) (int64, error) {
+	sliceSize := dm.cfg.ConsistentHashing.SliceSize
+	if sliceSize <= 0 {
+		sliceSize = dm.chunkSize
+	}
+
+	var written int64
+	start := chunk.start + chunk.current
+	end := chunk.start + chunk.size
+
+	for start < end {
+		sliceIndex := start / sliceSize
+		sliceEnd := (sliceIndex + 1) * sliceSize
+		if sliceEnd > end {
+			sliceEnd = end
+		}
+
+		num, err := dm.fetchSliceWithFailover(chunk, sliceIndex, start, sliceEnd)
+		written += num
+		if err != nil {
+			return written, err
+		}
+
+		start = sliceEnd
+	}
+
+	return written, nil
+}
+
+// fetchSliceWithFailover fetches [start, end) of chunk, trying each host in the rendezvous hash ring in
+// order before finally falling back to the original url.
+func (dm *downloadManager) fetchSliceWithFailover(chunk *DownloadChunk, sliceIndex, start, end int64) (int64, error) {
+	ch := dm.cfg.ConsistentHashing
+
+	var lastErr error
+	for _, host := range rendezvousOrder(ch.Hosts, urlPath(dm.url), sliceIndex) {
+		mirrorURL, err := rewriteHost(dm.url, host)
+		if err != nil {
+			lastErr = &DownloadError{Message: "Failed to rewrite host", Err: err}
+			continue
+		}
+
+		num, err := dm.fetchRange(mirrorURL, chunk, start, end)
+		if err == nil {
+			return num, nil
+		}
+		lastErr = err
+	}
+
+	num, err := dm.fetchRange(dm.url, chunk, start, end)
+	if err != nil {
+		return 0, lastErr
+	}
+	return num, nil
+}
+
+// fetchRange issues a single Range request against url for [start, end) and writes the response into
+// chunk's underlying writer.
+func (dm *downloadManager) fetchRange(url string, chunk *DownloadChunk, start, end int64) (int64, error) {
+	req, err := http.NewRequestWithContext(dm.ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, &DownloadError{Message: "Failed to create request", Err: err}
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end-1))
+
+	dm.acquireRequest()
+	defer dm.releaseRequest()
+
+	resp, err := dm.httpClient().Do(req)
+	if err != nil {
+		return 0, &DownloadError{Message: "Failed to do request", Err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusPartialContent {
+		return 0, &DownloadError{
+			Message:    "Failed to download chunk",
+			StatusCode: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+
+	counted := &countingReader{r: resp.Body, onRead: func(n int64) {
+		dm.onChunkProgress(int(chunk.index), n)
+	}}
+	return io.Copy(chunk, counted)
+}
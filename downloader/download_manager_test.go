@@ -0,0 +1,79 @@
+package downloader
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// failingHTTPClient is an HTTPClient test double whose Do always fails, e.g. simulating DNS failure or
+// a network being down, without ever returning a *http.Response.
+type failingHTTPClient struct {
+	err error
+}
+
+func (c *failingHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	return nil, c.err
+}
+
+// statusHTTPClient is an HTTPClient test double that always returns the given status code.
+type statusHTTPClient struct {
+	statusCode int
+}
+
+func (c *statusHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: c.statusCode,
+		Body:       io.NopCloser(strings.NewReader("")),
+		Header:     make(http.Header),
+	}, nil
+}
+
+func TestDoHeadRequestReturnsErrorInsteadOfPanicking(t *testing.T) {
+	dm := &downloadManager{
+		ctx: context.Background(),
+		cfg: &DownloaderConfig{HTTPClient: &failingHTTPClient{err: errors.New("connection refused")}},
+	}
+
+	_, err := dm.doHeadRequest("https://example.com/f.bin")
+	if err == nil {
+		t.Fatalf("doHeadRequest() error = nil, want the client's error")
+	}
+}
+
+func TestDoGetZeroReturnsErrorInsteadOfPanicking(t *testing.T) {
+	dm := &downloadManager{
+		ctx: context.Background(),
+		cfg: &DownloaderConfig{HTTPClient: &failingHTTPClient{err: errors.New("connection refused")}},
+	}
+
+	_, err := dm.doGetZero("https://example.com/f.bin")
+	if err == nil {
+		t.Fatalf("doGetZero() error = nil, want the client's error")
+	}
+}
+
+func TestFetchRangeSetsStatusCodeOnNonPartialContent(t *testing.T) {
+	dm := &downloadManager{
+		ctx: context.Background(),
+		cfg: &DownloaderConfig{HTTPClient: &statusHTTPClient{statusCode: http.StatusNotFound}},
+	}
+	buf := &memoryWriter{buf: make([]byte, 10)}
+	chunk := &DownloadChunk{start: 0, size: 10, writer: buf}
+
+	_, err := dm.fetchRange("https://example.com/f.bin", chunk, 0, 10)
+	if err == nil {
+		t.Fatalf("fetchRange() error = nil, want an error for a 404 response")
+	}
+
+	de, ok := err.(*DownloadError)
+	if !ok {
+		t.Fatalf("fetchRange() error type = %T, want *DownloadError", err)
+	}
+	if de.StatusCode != http.StatusNotFound {
+		t.Fatalf("DownloadError.StatusCode = %d, want %d (so a permanent error isn't retried forever)", de.StatusCode, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,368 @@
+package downloader
+
+import (
+	"context"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+type DownloaderConfig struct {
+	// The maximum of retry times for file
+	//
+	// If some error occurs when downloading a file, entire file will be re-downloaded.
+	//
+	// Default is 5.
+	MaxRetries int
+
+	// The maximum of concurrent downloads
+	//
+	// If `MaxConcurrentDownloads` is -1, mean that all the chunks will be downloaded concurrently.
+	//
+	// If `MaxConcurrentDownloads` is greater than 0, mean that
+	// the chunks will be downloaded concurrently by `MaxConcurrentDownloads` goroutines.
+	MaxConcurrentDownloads int
+
+	// This function determines how many chunks will be split.
+	//
+	// You can use the default function `DefaultPartDeterminer` or write your own function.
+	//
+	// You should prefer using this option over `ChunkSizeDeterminer`.
+	//
+	// You should use `PartDeterminer` either `ChunkSizeDeterminer`, not both.
+	PartDeterminerFunc PartDeterminer
+
+	// This function determines the size of each chunk.
+	//
+	// You can use the default function `DefaultChunkSizeDeterminer` or write your own function.
+	//
+	// You should prefer using `PartDeterminerFunc` over this option.
+	//
+	// You should use `PartDeterminerFunc` either `ChunkSizeDeterminerFunc`, not both.
+	ChunkSizeDeterminerFunc ChunkSizeDeterminer
+
+	// The size of each chunk
+	//
+	// If it is set greater than 0, mean that the chunks will be downloaded by the given size.
+	//
+	// Otherwise, the chunks will be downloaded by the size determined by `PartDeterminerFunc` if given, then `ChunkSizeDeterminerFunc`.
+	ChunkSize int64
+
+	// If set, chunk byte ranges are dispatched across a fleet of mirror hosts by consistent hashing
+	// instead of always hitting the original url, so repeated fetches of overlapping ranges land on
+	// the same upstream and hit warm CDN cache.
+	ConsistentHashing *CHConfig
+
+	// The maximum number of files downloaded concurrently by `DownloadManifest`.
+	//
+	// If `MaxConcurrentFiles` is <= 0, mean that all the files will be downloaded concurrently.
+	MaxConcurrentFiles int
+
+	// The maximum number of in-flight HTTP requests across all files in a `DownloadManifest` call.
+	//
+	// This bounds the total number of chunk requests in flight at once, on top of the per-file
+	// `MaxConcurrentDownloads` limit, so a manifest of many small files doesn't spawn thousands of
+	// goroutines while a single huge file can still saturate `MaxConcurrentDownloads`.
+	//
+	// If `MaxConcurrentRequests` is <= 0, mean that requests are not bounded across files.
+	MaxConcurrentRequests int
+
+	// If set, the first file in a `DownloadManifest` call to fail cancels the remaining downloads
+	// instead of letting siblings keep going.
+	ManifestFailFast bool
+
+	// The HTTP client used to issue every request.
+	//
+	// Inject a custom client for TLS pinning, auth headers, proxies, or test doubles. If it is nil,
+	// `http.DefaultClient` is used.
+	HTTPClient HTTPClient
+
+	// Controls the delay between chunk retries.
+	//
+	// If it is nil, `defaultRetryPolicy` is used.
+	RetryPolicy *RetryPolicy
+
+	// If set, `Download`/`DownloadWithContext` persist progress to a `<filename>.godl` sidecar file as
+	// chunks complete, and skip re-downloading chunks a previous attempt already finished, as long as
+	// the remote file's ETag/Last-Modified still match. The sidecar is removed on clean completion.
+	Resume bool
+
+	// If set, Progress is notified of download lifecycle events: when the file size is known, when
+	// each chunk starts/progresses/finishes, and when the whole download finishes.
+	//
+	// Use `NewTTYProgress` for a stderr renderer, or `NewMetricsProgress` for Prometheus-friendly
+	// counters.
+	Progress Progress
+}
+
+// HTTPClient is the interface `DownloaderConfig.HTTPClient` must satisfy. `*http.Client` already
+// satisfies it.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// RetryPolicy configures the backoff between chunk retries.
+//
+// The delay for attempt N (0-indexed) is `InitialDelay * Multiplier^N`, capped at `MaxDelay` and
+// randomized by `Jitter`. A `Retry-After` header on a 429/503 response overrides the computed delay.
+type RetryPolicy struct {
+	// InitialDelay is the delay before the first retry.
+	InitialDelay time.Duration
+
+	// MaxDelay caps the delay between retries, no matter how many attempts have been made.
+	MaxDelay time.Duration
+
+	// Multiplier grows the delay exponentially after each attempt.
+	Multiplier float64
+
+	// Jitter is the maximum fraction of the computed delay randomly added or subtracted, to avoid
+	// many chunks retrying in lockstep. 0.1 means +/-10%.
+	Jitter float64
+
+	// Retryable reports whether a response with the given status code should be retried.
+	//
+	// If it is nil, `defaultRetryableStatus` is used.
+	Retryable func(statusCode int) bool
+}
+
+func defaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		InitialDelay: 200 * time.Millisecond,
+		MaxDelay:     10 * time.Second,
+		Multiplier:   2,
+		Jitter:       0.1,
+	}
+}
+
+func defaultRetryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// Delay returns the backoff delay before the given 0-indexed attempt.
+func (rp *RetryPolicy) Delay(attempt int) time.Duration {
+	delay := float64(rp.InitialDelay) * math.Pow(rp.Multiplier, float64(attempt))
+	if rp.MaxDelay > 0 && delay > float64(rp.MaxDelay) {
+		delay = float64(rp.MaxDelay)
+	}
+
+	if rp.Jitter > 0 {
+		delay += delay * rp.Jitter * (2*rand.Float64() - 1)
+	}
+
+	if delay < 0 {
+		return 0
+	}
+	return time.Duration(delay)
+}
+
+// Retry reports whether a response with the given status code should be retried.
+func (rp *RetryPolicy) Retry(statusCode int) bool {
+	if rp.Retryable != nil {
+		return rp.Retryable(statusCode)
+	}
+	return defaultRetryableStatus(statusCode)
+}
+
+// CHConfig configures consistent-hashing dispatch of chunk requests across a fleet of mirror hosts.
+//
+// A chunk's byte range is split into sub-requests aligned to `SliceSize`, and each sub-range's slice
+// index is hashed against `Hosts` using rendezvous hashing, so the same slice of the same url always
+// maps to the same mirror. If a mirror fails, the next host in the hash ring is tried, and finally the
+// original url, so behavior degrades gracefully instead of failing the whole chunk.
+type CHConfig struct {
+	// The mirror hosts to distribute chunk requests across, e.g. "cdn1.example.com".
+	Hosts []string
+
+	// The size, in bytes, that chunk byte ranges are aligned to before hashing.
+	//
+	// If it is not set, `ChunkSize` is used.
+	SliceSize int64
+}
+
+type Downloader struct {
+	config DownloaderConfig
+}
+
+// Enum size units
+const (
+	_ = 1 << (10 * iota)
+	KB
+	MB
+	GB
+)
+
+var defaultPartDeterminer = func(totalSize int64) int64 {
+	if totalSize < 1*MB {
+		return 1
+	}
+
+	if totalSize < 10*MB {
+		return 4
+	}
+
+	if totalSize < 100*MB {
+		return 16
+	}
+
+	return 32
+}
+
+func defaultDownloaderConfiguration() DownloaderConfig {
+	return DownloaderConfig{
+		MaxRetries:             5,
+		MaxConcurrentDownloads: -1,
+		PartDeterminerFunc:     defaultPartDeterminer,
+		ChunkSizeDeterminerFunc: func(totalSize int64) int64 {
+			return totalSize / defaultPartDeterminer(totalSize)
+		},
+		HTTPClient:  http.DefaultClient,
+		RetryPolicy: defaultRetryPolicy(),
+	}
+}
+
+// Return new Downloader with default configuration
+//
+// Default configuration:
+//
+//	MaxRetries: 5
+//	MaxConcurrentDownloads: -1
+//	PartDeterminerFunc: defaultPartDeterminer
+//	ChunkSizeDeterminerFunc: defaultChunkSizeDeterminer
+func NewDownloader() *Downloader {
+	return NewDownloaderWithConfig(defaultDownloaderConfiguration())
+}
+
+// Return new Downloader with custom configuration
+func NewDownloaderWithConfig(config DownloaderConfig) *Downloader {
+	return &Downloader{config: config}
+}
+
+type DownloadOption func(*Downloader)
+
+// Config the maximum of retry times for file
+//
+// If some error occurs when downloading a file, entire file will be re-downloaded.
+//
+// Default is 5.
+func WithMaxRetries(maxRetries int) DownloadOption {
+	return func(d *Downloader) {
+		d.config.MaxRetries = maxRetries
+	}
+}
+
+// Config the maximum of concurrent downloads
+//
+// If `MaxConcurrentDownloads` is -1, mean that all the chunks will be downloaded concurrently.
+//
+// If `MaxConcurrentDownloads` is greater than 0, mean that
+// the chunks will be downloaded concurrently by `MaxConcurrentDownloads` goroutines.
+func WithMaxConcurrentDownloads(maxConcurrentDownloads int) DownloadOption {
+	return func(d *Downloader) {
+		d.config.MaxConcurrentDownloads = maxConcurrentDownloads
+	}
+}
+
+// Config the function determines how many chunks will be split.
+//
+// You can use the default function `DefaultPartDeterminer` or write your own function.
+//
+// You should prefer using this option over `ChunkSizeDeterminer`.
+//
+// You should use `WithPartDeterminerFunc` either `WithChunkSizeDeterminerFunc`, not both.
+func WithPartDeterminerFunc(partDeterminerFunc PartDeterminer) DownloadOption {
+	return func(d *Downloader) {
+		d.config.PartDeterminerFunc = partDeterminerFunc
+	}
+}
+
+// Config the function determines the size of each chunk.
+//
+// You can use the default function `DefaultChunkSizeDeterminer` or write your own function.
+//
+// You should prefer using `WithPartDeterminerFunc` over this option.
+//
+// You should use `WithPartDeterminerFunc` either `WithChunkSizeDeterminerFunc`, not both.
+func WithChunkSizeDeterminerFunc(chunkSizeDeterminerFunc ChunkSizeDeterminer) DownloadOption {
+	return func(d *Downloader) {
+		d.config.ChunkSizeDeterminerFunc = chunkSizeDeterminerFunc
+	}
+}
+
+// Config the HTTP client used to issue every request.
+//
+// Use this to inject a custom transport for TLS pinning, auth headers, proxies, or test doubles.
+func WithHTTPClient(client HTTPClient) DownloadOption {
+	return func(d *Downloader) {
+		d.config.HTTPClient = client
+	}
+}
+
+// Config the backoff policy between chunk retries.
+func WithRetryPolicy(retryPolicy *RetryPolicy) DownloadOption {
+	return func(d *Downloader) {
+		d.config.RetryPolicy = retryPolicy
+	}
+}
+
+// Config whether to resume an interrupted download from its `<filename>.godl` sidecar file, instead of
+// always starting over.
+func WithResume(resume bool) DownloadOption {
+	return func(d *Downloader) {
+		d.config.Resume = resume
+	}
+}
+
+// Return new Downloader with custom options
+func NewWithOptions(options ...DownloadOption) *Downloader {
+	downloader := NewDownloader()
+	for _, option := range options {
+		option(downloader)
+	}
+	return downloader
+}
+
+// ---------------------------- Implement IDownloader ----------------------------
+
+// Download the file from the given url and save it to the given filename.
+func (d *Downloader) Download(url string, filename string) (int64, error) {
+	return d.DownloadWithContext(context.Background(), url, filename)
+}
+
+// Download the file from the given url and save it to the given filename.
+//
+// The context is used to cancel the download operation.
+func (d *Downloader) DownloadWithContext(ctx context.Context, url string, filename string) (int64, error) {
+	file, err := createFile(filename)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	fw := &FileWriter{file: file}
+
+	downloadManager := &downloadManager{ctx: ctx, url: url, filename: filename, writer: fw, cfg: &d.config}
+	return downloadManager.download()
+}
+
+// Fetch streams the file from the given url as an io.ReadCloser, instead of writing it to disk.
+//
+// The returned reader can be consumed immediately, in parallel with the background chunk downloads,
+// which makes it suitable for piping into decoders, tar extractors, S3 uploads, and the like without
+// requiring an intermediate file. It also reports the total size of the file, as returned by the server.
+//
+// The context is used to cancel the download operation; the caller is responsible for closing the reader.
+//
+// Progress and ConsistentHashing are honored the same as Download. Resume is not: it persists a
+// `<filename>.godl` sidecar next to the destination file, and Fetch has no destination file to persist
+// one against, so `DownloaderConfig.Resume` has no effect here.
+func (d *Downloader) Fetch(ctx context.Context, url string) (io.ReadCloser, int64, error) {
+	downloadManager := &downloadManager{ctx: ctx, url: url, cfg: &d.config}
+	return downloadManager.fetch()
+}
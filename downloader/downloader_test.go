@@ -0,0 +1,54 @@
+package downloader
+
+import "testing"
+
+func TestRetryPolicyDelayGrowsExponentially(t *testing.T) {
+	rp := &RetryPolicy{InitialDelay: 0, MaxDelay: 0, Multiplier: 2, Jitter: 0}
+
+	for attempt, want := range map[int]int64{0: 1, 1: 2, 2: 4, 3: 8} {
+		rp.InitialDelay = 1
+		if got := rp.Delay(attempt); int64(got) != want {
+			t.Fatalf("Delay(%d) = %d, want %d", attempt, got, want)
+		}
+	}
+}
+
+func TestRetryPolicyDelayCapsAtMaxDelay(t *testing.T) {
+	rp := &RetryPolicy{InitialDelay: 1000, MaxDelay: 5000, Multiplier: 2, Jitter: 0}
+
+	if got, want := rp.Delay(10), int64(5000); int64(got) != want {
+		t.Fatalf("Delay(10) = %d, want capped at %d", got, want)
+	}
+}
+
+func TestRetryPolicyDelayNeverNegative(t *testing.T) {
+	rp := &RetryPolicy{InitialDelay: 100, MaxDelay: 0, Multiplier: 1, Jitter: 5}
+
+	for attempt := 0; attempt < 20; attempt++ {
+		if rp.Delay(attempt) < 0 {
+			t.Fatalf("Delay(%d) returned a negative duration", attempt)
+		}
+	}
+}
+
+func TestRetryPolicyRetryUsesDefaultWhenRetryableUnset(t *testing.T) {
+	rp := defaultRetryPolicy()
+
+	if !rp.Retry(503) {
+		t.Fatalf("Retry(503) = false, want true")
+	}
+	if rp.Retry(404) {
+		t.Fatalf("Retry(404) = true, want false")
+	}
+}
+
+func TestRetryPolicyRetryUsesCustomRetryable(t *testing.T) {
+	rp := &RetryPolicy{Retryable: func(statusCode int) bool { return statusCode == 418 }}
+
+	if !rp.Retry(418) {
+		t.Fatalf("Retry(418) = false, want true")
+	}
+	if rp.Retry(503) {
+		t.Fatalf("Retry(503) = true, want false")
+	}
+}
@@ -0,0 +1,9 @@
+package downloader
+
+import "os"
+
+// createFile opens filename for writing. It uses O_RDWR rather than O_WRONLY so that, when resume is
+// enabled, bytes written by a previous attempt survive being reopened rather than being truncated away.
+func createFile(filename string) (*os.File, error) {
+	return os.OpenFile(filename, os.O_CREATE|os.O_RDWR, 0644)
+}
@@ -1,8 +1,9 @@
-package godownloader
+package downloader
 
 import (
 	"context"
 	"fmt"
+	"time"
 )
 
 // WriteAt is the interface that wraps the basic WriteAt method.
@@ -36,6 +37,12 @@ type IDownloader interface {
 type DownloadError struct {
 	Message string
 	Err     error
+
+	// StatusCode is the HTTP response status that caused the error, if any.
+	StatusCode int
+
+	// RetryAfter is the delay requested by the server's `Retry-After` header, if any.
+	RetryAfter time.Duration
 }
 
 func (e *DownloadError) Error() string {
@@ -0,0 +1,120 @@
+package downloader
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// ManifestEntry is a single file to download as part of a manifest passed to `DownloadManifest`.
+type ManifestEntry struct {
+	URL      string
+	Filename string
+}
+
+// ManifestError reports which urls in a `DownloadManifest` call failed, without aborting the siblings
+// that succeeded (unless `DownloaderConfig.ManifestFailFast` is set).
+type ManifestError struct {
+	// Failures maps a failed entry's URL to the error that occurred while downloading it.
+	Failures map[string]error
+}
+
+func (e *ManifestError) Error() string {
+	return fmt.Sprintf("ManifestError{Failures=%d}", len(e.Failures))
+}
+
+// semaphore bounds the number of concurrent holders of a resource.
+//
+// A limit <= 0 means unlimited: acquire/release are no-ops.
+type semaphore struct {
+	tokens chan struct{}
+}
+
+func newSemaphore(limit int) *semaphore {
+	if limit <= 0 {
+		return &semaphore{}
+	}
+	return &semaphore{tokens: make(chan struct{}, limit)}
+}
+
+func (s *semaphore) acquire() {
+	if s.tokens != nil {
+		s.tokens <- struct{}{}
+	}
+}
+
+func (s *semaphore) release() {
+	if s.tokens != nil {
+		<-s.tokens
+	}
+}
+
+// DownloadManifest downloads every entry in manifest concurrently, bounded by `MaxConcurrentFiles` files
+// in flight and `MaxConcurrentRequests` HTTP requests in flight across all of them combined. This keeps a
+// manifest of hundreds of small files from spawning thousands of goroutines, while a single huge file can
+// still saturate `MaxConcurrentDownloads`.
+//
+// Per-file errors are collected into a *ManifestError instead of aborting siblings, unless
+// `ManifestFailFast` is set, in which case the first error cancels the remaining downloads.
+func (d *Downloader) DownloadManifest(ctx context.Context, manifest []ManifestEntry) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	fileSem := newSemaphore(d.config.MaxConcurrentFiles)
+	requestSem := newSemaphore(d.config.MaxConcurrentRequests)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	failures := map[string]error{}
+
+	recordFailure := func(entry ManifestEntry, err error) {
+		mu.Lock()
+		failures[entry.URL] = err
+		mu.Unlock()
+		if d.config.ManifestFailFast {
+			cancel()
+		}
+	}
+
+	for _, entry := range manifest {
+		entry := entry
+
+		fileSem.acquire()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer fileSem.release()
+
+			if ctx.Err() != nil {
+				return
+			}
+
+			file, err := createFile(entry.Filename)
+			if err != nil {
+				recordFailure(entry, err)
+				return
+			}
+			defer file.Close()
+			fw := &FileWriter{file: file}
+
+			downloadManager := &downloadManager{
+				ctx:        ctx,
+				url:        entry.URL,
+				filename:   entry.Filename,
+				writer:     fw,
+				cfg:        &d.config,
+				requestSem: requestSem,
+			}
+			if _, err := downloadManager.download(); err != nil {
+				recordFailure(entry, err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if len(failures) > 0 {
+		return &ManifestError{Failures: failures}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package downloader
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// rangeServingHandler serves body, honoring Range/HEAD requests the way downloadManager expects: a
+// Content-Length on HEAD, and 206 Partial Content with Content-Range on a ranged GET.
+func rangeServingHandler(body []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.Header().Set("Content-Length", fmt.Sprintf("%d", len(body)))
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		start, end := int64(0), int64(len(body)-1)
+		if rng := r.Header.Get("Range"); rng != "" {
+			fmt.Sscanf(rng, "bytes=%d-%d", &start, &end)
+		}
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, len(body)))
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(body[start : end+1])
+	}
+}
+
+func TestDownloadManifestConcurrentSuccessAndFailure(t *testing.T) {
+	ok := httptest.NewServer(rangeServingHandler([]byte("hello manifest world")))
+	defer ok.Close()
+
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	down.Close() // closed before use, so every request to it fails to connect
+
+	dir := t.TempDir()
+	manifest := []ManifestEntry{
+		{URL: ok.URL + "/a", Filename: filepath.Join(dir, "a.bin")},
+		{URL: down.URL + "/b", Filename: filepath.Join(dir, "b.bin")},
+		{URL: ok.URL + "/c", Filename: filepath.Join(dir, "c.bin")},
+		{URL: down.URL + "/d", Filename: filepath.Join(dir, "d.bin")},
+	}
+
+	config := defaultDownloaderConfiguration()
+	config.MaxConcurrentFiles = 2
+	config.MaxConcurrentRequests = 2
+	d := NewDownloaderWithConfig(config)
+
+	err := d.DownloadManifest(context.Background(), manifest)
+	if err == nil {
+		t.Fatalf("DownloadManifest() error = nil, want a *ManifestError for the two unreachable urls")
+	}
+
+	me, isManifestErr := err.(*ManifestError)
+	if !isManifestErr {
+		t.Fatalf("DownloadManifest() error type = %T, want *ManifestError", err)
+	}
+	if len(me.Failures) != 2 {
+		t.Fatalf("len(Failures) = %d, want 2", len(me.Failures))
+	}
+	if _, ok := me.Failures[down.URL+"/b"]; !ok {
+		t.Fatalf("Failures missing entry for %s", down.URL+"/b")
+	}
+	if _, ok := me.Failures[down.URL+"/d"]; !ok {
+		t.Fatalf("Failures missing entry for %s", down.URL+"/d")
+	}
+
+	for _, f := range []string{"a.bin", "c.bin"} {
+		got, readErr := os.ReadFile(filepath.Join(dir, f))
+		if readErr != nil {
+			t.Fatalf("ReadFile(%s) error = %v, want the successful entry's content on disk", f, readErr)
+		}
+		if string(got) != "hello manifest world" {
+			t.Fatalf("content of %s = %q, want %q", f, got, "hello manifest world")
+		}
+	}
+}
+
+func TestDownloadManifestFailFastCancelsRemaining(t *testing.T) {
+	var slowRequests int32
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&slowRequests, 1)
+		select {
+		case <-time.After(2 * time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer slow.Close()
+
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	down.Close()
+
+	dir := t.TempDir()
+	manifest := []ManifestEntry{
+		{URL: down.URL + "/fails-fast", Filename: filepath.Join(dir, "fails.bin")},
+	}
+	for i := 0; i < 5; i++ {
+		manifest = append(manifest, ManifestEntry{
+			URL:      fmt.Sprintf("%s/slow-%d", slow.URL, i),
+			Filename: filepath.Join(dir, fmt.Sprintf("slow-%d.bin", i)),
+		})
+	}
+
+	config := defaultDownloaderConfiguration()
+	config.MaxConcurrentFiles = len(manifest)
+	config.MaxConcurrentRequests = len(manifest)
+	config.ManifestFailFast = true
+	d := NewDownloaderWithConfig(config)
+
+	start := time.Now()
+	err := d.DownloadManifest(context.Background(), manifest)
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Fatalf("DownloadManifest() error = nil, want a *ManifestError")
+	}
+	if elapsed >= 2*time.Second {
+		t.Fatalf("DownloadManifest() took %s, want well under the 2s slow-handler delay since ManifestFailFast should cancel the in-flight siblings", elapsed)
+	}
+}
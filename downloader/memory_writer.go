@@ -0,0 +1,28 @@
+package downloader
+
+import "fmt"
+
+// memoryWriter is a WriteAtWriter backed by an in-memory buffer, sized up front. Fetch uses it to
+// buffer a chunk's bytes when dispatching via consistent hashing, since a streaming fetch has no
+// destination file to address with WriteAt.
+type memoryWriter struct {
+	buf []byte
+}
+
+func (mw *memoryWriter) WriteAt(p []byte, off int64) (int, error) {
+	if off < 0 || off+int64(len(p)) > int64(len(mw.buf)) {
+		return 0, fmt.Errorf("memoryWriter: write out of bounds: off=%d len=%d size=%d", off, len(p), len(mw.buf))
+	}
+	return copy(mw.buf[off:], p), nil
+}
+
+// offsetWriter adapts a WriteAtWriter whose valid offsets start at 0 so it can be addressed by a
+// DownloadChunk, whose WriteAt offsets are absolute within the whole file starting at chunk.start.
+type offsetWriter struct {
+	base  int64
+	inner WriteAtWriter
+}
+
+func (ow *offsetWriter) WriteAt(p []byte, off int64) (int, error) {
+	return ow.inner.WriteAt(p, off-ow.base)
+}
@@ -0,0 +1,79 @@
+package downloader
+
+import "io"
+
+// Progress receives callbacks about a download's lifecycle.
+//
+// OnChunkStart, OnChunkProgress, and OnChunkFinish are called concurrently, one set of calls per
+// in-flight chunk, so implementations must be safe for concurrent use.
+type Progress interface {
+	// OnStart is called once, after the remote file's size is known.
+	OnStart(total int64)
+
+	// OnChunkStart is called once per chunk, before its first byte is requested.
+	OnChunkStart(id int, size int64)
+
+	// OnChunkProgress is called every time bytes are read from a chunk's response body, with the
+	// number of bytes read since the last call. It fires as bytes arrive, without waiting for the
+	// chunk to finish.
+	OnChunkProgress(id int, delta int64)
+
+	// OnChunkFinish is called once per chunk, after it has stopped being retried, successfully or not.
+	OnChunkFinish(id int)
+
+	// OnFinish is called once, after the whole download has finished or failed.
+	OnFinish(written int64, err error)
+}
+
+// Config the Progress consumer notified of download lifecycle events.
+func WithProgress(progress Progress) DownloadOption {
+	return func(d *Downloader) {
+		d.config.Progress = progress
+	}
+}
+
+func (dm *downloadManager) onStart(total int64) {
+	if dm.cfg.Progress != nil {
+		dm.cfg.Progress.OnStart(total)
+	}
+}
+
+func (dm *downloadManager) onChunkStart(id int, size int64) {
+	if dm.cfg.Progress != nil {
+		dm.cfg.Progress.OnChunkStart(id, size)
+	}
+}
+
+func (dm *downloadManager) onChunkProgress(id int, delta int64) {
+	if dm.cfg.Progress != nil {
+		dm.cfg.Progress.OnChunkProgress(id, delta)
+	}
+}
+
+func (dm *downloadManager) onChunkFinish(id int) {
+	if dm.cfg.Progress != nil {
+		dm.cfg.Progress.OnChunkFinish(id)
+	}
+}
+
+func (dm *downloadManager) onFinish(written int64, err error) {
+	if dm.cfg.Progress != nil {
+		dm.cfg.Progress.OnFinish(written, err)
+	}
+}
+
+// countingReader wraps an io.Reader, invoking onRead with the number of bytes read after every Read
+// call that returns data, so byte-level progress can be reported without waiting for the chunk it
+// belongs to to finish.
+type countingReader struct {
+	r      io.Reader
+	onRead func(n int64)
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	if n > 0 && cr.onRead != nil {
+		cr.onRead(int64(n))
+	}
+	return n, err
+}
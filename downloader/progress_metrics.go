@@ -0,0 +1,59 @@
+package downloader
+
+import "sync/atomic"
+
+// MetricsProgress accumulates download counters suitable for exposing to Prometheus, e.g. backing a
+// prometheus.GaugeFunc/CounterFunc with BytesWritten, ChunksInFlight, ChunksFinished, and Failed. It is
+// safe for concurrent use.
+type MetricsProgress struct {
+	bytesWritten   int64
+	chunksInFlight int64
+	chunksFinished int64
+	failed         int64
+}
+
+// NewMetricsProgress returns a new MetricsProgress.
+func NewMetricsProgress() *MetricsProgress {
+	return &MetricsProgress{}
+}
+
+func (p *MetricsProgress) OnStart(total int64) {}
+
+func (p *MetricsProgress) OnChunkStart(id int, size int64) {
+	atomic.AddInt64(&p.chunksInFlight, 1)
+}
+
+func (p *MetricsProgress) OnChunkProgress(id int, delta int64) {
+	atomic.AddInt64(&p.bytesWritten, delta)
+}
+
+func (p *MetricsProgress) OnChunkFinish(id int) {
+	atomic.AddInt64(&p.chunksInFlight, -1)
+	atomic.AddInt64(&p.chunksFinished, 1)
+}
+
+func (p *MetricsProgress) OnFinish(written int64, err error) {
+	if err != nil {
+		atomic.AddInt64(&p.failed, 1)
+	}
+}
+
+// BytesWritten returns the total number of bytes written so far across all chunks.
+func (p *MetricsProgress) BytesWritten() int64 {
+	return atomic.LoadInt64(&p.bytesWritten)
+}
+
+// ChunksInFlight returns the number of chunks currently being downloaded.
+func (p *MetricsProgress) ChunksInFlight() int64 {
+	return atomic.LoadInt64(&p.chunksInFlight)
+}
+
+// ChunksFinished returns the number of chunks that have stopped being retried, successfully or not.
+func (p *MetricsProgress) ChunksFinished() int64 {
+	return atomic.LoadInt64(&p.chunksFinished)
+}
+
+// Failed returns the number of downloads that finished with an error.
+func (p *MetricsProgress) Failed() int64 {
+	return atomic.LoadInt64(&p.failed)
+}
@@ -0,0 +1,62 @@
+package downloader
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// TTYProgress renders download progress to stderr as a single, repeatedly overwritten line.
+//
+// It is safe for concurrent use: chunk callbacks arrive from multiple goroutines, one per in-flight
+// chunk.
+type TTYProgress struct {
+	mu      sync.Mutex
+	total   int64
+	written int64
+}
+
+// NewTTYProgress returns a new TTYProgress.
+func NewTTYProgress() *TTYProgress {
+	return &TTYProgress{}
+}
+
+func (p *TTYProgress) OnStart(total int64) {
+	p.mu.Lock()
+	p.total = total
+	p.mu.Unlock()
+	p.render()
+}
+
+func (p *TTYProgress) OnChunkStart(id int, size int64) {}
+
+func (p *TTYProgress) OnChunkProgress(id int, delta int64) {
+	p.mu.Lock()
+	p.written += delta
+	p.mu.Unlock()
+	p.render()
+}
+
+func (p *TTYProgress) OnChunkFinish(id int) {}
+
+func (p *TTYProgress) OnFinish(written int64, err error) {
+	p.mu.Lock()
+	p.written = written
+	p.mu.Unlock()
+	p.render()
+	fmt.Fprintln(os.Stderr)
+}
+
+func (p *TTYProgress) render() {
+	p.mu.Lock()
+	total, written := p.total, p.written
+	p.mu.Unlock()
+
+	if total <= 0 {
+		fmt.Fprintf(os.Stderr, "\r%d bytes downloaded", written)
+		return
+	}
+
+	percent := float64(written) / float64(total) * 100
+	fmt.Fprintf(os.Stderr, "\r%d/%d bytes (%.1f%%)", written, total, percent)
+}
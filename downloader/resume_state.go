@@ -0,0 +1,100 @@
+package downloader
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// resumeState is the on-disk sidecar (`<filename>.godl`) that lets an interrupted download resume
+// instead of starting over. It tracks which chunks have already been written, plus enough about the
+// remote file to detect whether it changed since the previous attempt.
+type resumeState struct {
+	URL          string `json:"url"`
+	TotalSize    int64  `json:"total_size"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	ChunkSize    int64  `json:"chunk_size"`
+	Completed    []bool `json:"completed"`
+
+	mu sync.Mutex
+}
+
+func sidecarPath(filename string) string {
+	return filename + ".godl"
+}
+
+// loadResumeState reads filename's sidecar, if any.
+func loadResumeState(filename string) (*resumeState, error) {
+	data, err := os.ReadFile(sidecarPath(filename))
+	if err != nil {
+		return nil, err
+	}
+
+	state := &resumeState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// matches reports whether state still describes the current remote file, so the chunks it marks
+// complete can be trusted. It requires at least one matching validator (ETag or Last-Modified); if the
+// server sent neither, the remote file is treated as changed and the sidecar is discarded.
+func (s *resumeState) matches(url string, totalSize int64, etag string, lastModified string) bool {
+	if s.URL != url || s.TotalSize != totalSize {
+		return false
+	}
+	if s.ETag != "" && etag != "" {
+		return s.ETag == etag
+	}
+	if s.LastModified != "" && lastModified != "" {
+		return s.LastModified == lastModified
+	}
+	return false
+}
+
+// save persists state to its sidecar file and fsyncs it, so a crash mid-download can't leave behind a
+// sidecar that claims more progress than was actually made durable.
+func (s *resumeState) save(filename string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(sidecarPath(filename), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.Write(data); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// markComplete flips chunkIndex's bit and persists the sidecar immediately, so progress survives a
+// crash between chunks.
+func (s *resumeState) markComplete(filename string, chunkIndex int64) error {
+	s.mu.Lock()
+	s.Completed[chunkIndex] = true
+	s.mu.Unlock()
+
+	return s.save(filename)
+}
+
+// isComplete reports whether chunkIndex was already downloaded by a previous attempt.
+func (s *resumeState) isComplete(chunkIndex int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return chunkIndex < int64(len(s.Completed)) && s.Completed[chunkIndex]
+}
+
+func removeSidecar(filename string) {
+	os.Remove(sidecarPath(filename))
+}
@@ -0,0 +1,56 @@
+package downloader
+
+import "testing"
+
+func TestResumeStateMatches(t *testing.T) {
+	s := &resumeState{URL: "https://example.com/f.bin", TotalSize: 1000, ETag: "abc"}
+
+	if !s.matches("https://example.com/f.bin", 1000, "abc", "") {
+		t.Fatalf("matches() = false, want true for an identical ETag")
+	}
+	if s.matches("https://example.com/f.bin", 1000, "def", "") {
+		t.Fatalf("matches() = true, want false for a changed ETag")
+	}
+	if s.matches("https://example.com/other.bin", 1000, "abc", "") {
+		t.Fatalf("matches() = true, want false for a different url")
+	}
+	if s.matches("https://example.com/f.bin", 2000, "abc", "") {
+		t.Fatalf("matches() = true, want false for a different size")
+	}
+}
+
+func TestResumeStateMatchesFallsBackToLastModified(t *testing.T) {
+	s := &resumeState{URL: "https://example.com/f.bin", TotalSize: 1000, LastModified: "Mon, 01 Jan 2024 00:00:00 GMT"}
+
+	if !s.matches("https://example.com/f.bin", 1000, "", "Mon, 01 Jan 2024 00:00:00 GMT") {
+		t.Fatalf("matches() = false, want true for an identical Last-Modified")
+	}
+	if s.matches("https://example.com/f.bin", 1000, "", "Tue, 02 Jan 2024 00:00:00 GMT") {
+		t.Fatalf("matches() = true, want false for a changed Last-Modified")
+	}
+}
+
+func TestResumeStateMatchesRequiresAValidator(t *testing.T) {
+	s := &resumeState{URL: "https://example.com/f.bin", TotalSize: 1000}
+
+	if s.matches("https://example.com/f.bin", 1000, "", "") {
+		t.Fatalf("matches() = true, want false when neither side has a validator")
+	}
+}
+
+func TestResumeStateIsComplete(t *testing.T) {
+	s := &resumeState{Completed: []bool{true, false, true}}
+
+	if !s.isComplete(0) {
+		t.Fatalf("isComplete(0) = false, want true")
+	}
+	if s.isComplete(1) {
+		t.Fatalf("isComplete(1) = true, want false")
+	}
+	if !s.isComplete(2) {
+		t.Fatalf("isComplete(2) = false, want true")
+	}
+	if s.isComplete(5) {
+		t.Fatalf("isComplete(5) = true, want false for an out-of-range index")
+	}
+}
@@ -0,0 +1,51 @@
+package downloader
+
+import "sync"
+
+// chunkJob is a unit of work for a workQueue: fetch the byte range
+// [start, start+size) and populate reader with the result.
+type chunkJob struct {
+	start  int64
+	size   int64
+	index  int64
+	reader *bufferedReader
+}
+
+// workQueue runs chunkJobs across a bounded pool of goroutines, so streaming
+// downloads honor MaxConcurrentDownloads the same way the batched download()
+// path does.
+type workQueue struct {
+	jobs    chan chunkJob
+	wg      sync.WaitGroup
+	handler func(chunkJob)
+}
+
+func newWorkQueue(workers int, handler func(chunkJob)) *workQueue {
+	if workers <= 0 {
+		workers = 1
+	}
+
+	wq := &workQueue{jobs: make(chan chunkJob), handler: handler}
+	wq.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go wq.worker()
+	}
+	return wq
+}
+
+func (wq *workQueue) worker() {
+	defer wq.wg.Done()
+	for job := range wq.jobs {
+		wq.handler(job)
+	}
+}
+
+func (wq *workQueue) submit(job chunkJob) {
+	wq.jobs <- job
+}
+
+// close stops accepting new jobs and waits for in-flight jobs to finish.
+func (wq *workQueue) close() {
+	close(wq.jobs)
+	wq.wg.Wait()
+}